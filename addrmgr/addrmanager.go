@@ -0,0 +1,364 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package addrmgr gives the rosetta-ravencoin node its own view of the
+// Ravencoin peer-to-peer network, independent of raven-cli's
+// getpeerinfo. It keeps a persistent, bucketed store of KnownAddress
+// records and scores them so callers can pick good candidates to dial,
+// following the approach pioneered by btcd's addrmgr.
+package addrmgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/wire"
+)
+
+const (
+	// newBucketCount is the number of buckets used to group addresses
+	// that have not yet been successfully connected to.
+	newBucketCount = 1024
+
+	// triedBucketCount is the number of buckets used to group addresses
+	// that have been successfully connected to at least once.
+	triedBucketCount = 64
+
+	// peersFilename is the name of the file, inside the AddrManager's
+	// data directory, that the known address set is persisted to.
+	peersFilename = "peers.json"
+
+	// pruneInterval is how often Start's background loop sweeps the
+	// known address set for entries KnownAddress.isBad considers stale.
+	pruneInterval = time.Hour
+)
+
+// AddrManager keeps a bucketed store of KnownAddress records and scores
+// them so callers can pick good candidates to dial. It is safe for
+// concurrent use.
+type AddrManager struct {
+	mu        sync.Mutex
+	dataDir   string
+	addrIndex map[string]*KnownAddress // keyed by NetAddress.IP:Port
+	addrNew   [newBucketCount]map[string]*KnownAddress
+	addrTried [triedBucketCount]map[string]*KnownAddress
+	started   bool
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// New returns a new AddrManager that persists its known address set to
+// peers.json inside dataDir. Callers must call Stop when finished so the
+// address set is flushed to disk.
+func New(dataDir string) *AddrManager {
+	am := &AddrManager{
+		dataDir:   dataDir,
+		addrIndex: make(map[string]*KnownAddress),
+	}
+	for i := range am.addrNew {
+		am.addrNew[i] = make(map[string]*KnownAddress)
+	}
+	for i := range am.addrTried {
+		am.addrTried[i] = make(map[string]*KnownAddress)
+	}
+
+	return am
+}
+
+// Start loads the persisted known address set from disk, if any, and
+// launches the background loop that periodically prunes stale
+// addresses. It is a no-op if already started.
+func (a *AddrManager) Start() error {
+	a.mu.Lock()
+	if a.started {
+		a.mu.Unlock()
+		return nil
+	}
+	a.started = true
+	a.quit = make(chan struct{})
+
+	err := a.deserializePeers()
+	a.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("addrmgr: unable to load %s: %w", peersFilename, err)
+	}
+
+	a.wg.Add(1)
+	go a.pruneHandler()
+
+	return nil
+}
+
+// Stop halts the background prune loop and persists the known address
+// set to disk.
+func (a *AddrManager) Stop() error {
+	a.mu.Lock()
+	if !a.started {
+		defer a.mu.Unlock()
+		return a.serializePeers()
+	}
+	a.started = false
+	quit := a.quit
+	a.mu.Unlock()
+
+	close(quit)
+	a.wg.Wait()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.serializePeers()
+}
+
+// pruneHandler periodically removes stale addresses until Stop closes
+// a.quit.
+func (a *AddrManager) pruneHandler() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.prune()
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// key returns the string used to index a NetAddress in addrIndex.
+func key(na *wire.NetAddress) string {
+	return net.JoinHostPort(na.IP.String(), strconv.Itoa(int(na.Port)))
+}
+
+// AddAddress adds a new address to the new bucket derived from src,
+// recording src for source-address grouping. Addresses already known are
+// left untouched.
+func (a *AddrManager) AddAddress(na, src *wire.NetAddress) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	k := key(na)
+	if _, ok := a.addrIndex[k]; ok {
+		return
+	}
+
+	ka := &KnownAddress{na: na, srcAddr: src}
+	a.addrIndex[k] = ka
+
+	bucket := a.newBucket(na, src)
+	a.addrNew[bucket][k] = ka
+	ka.refs++
+}
+
+// Good marks an address as having been successfully connected to,
+// promoting it from the new buckets into a tried bucket.
+func (a *AddrManager) Good(na *wire.NetAddress) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ka, ok := a.addrIndex[key(na)]
+	if !ok {
+		return
+	}
+
+	ka.attempts = 0
+	ka.lastSuccess = time.Now()
+	ka.lastAttempt = ka.lastSuccess
+
+	if ka.tried {
+		return
+	}
+
+	k := key(na)
+	for _, bucket := range a.addrNew {
+		if _, ok := bucket[k]; ok {
+			delete(bucket, k)
+			ka.refs--
+		}
+	}
+
+	ka.tried = true
+	a.addrTried[a.triedBucket(na)][k] = ka
+}
+
+// Attempt marks that a connection to the address was just attempted,
+// recording the time and incrementing its failure count.
+func (a *AddrManager) Attempt(na *wire.NetAddress) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ka, ok := a.addrIndex[key(na)]
+	if !ok {
+		return
+	}
+
+	ka.lastAttempt = time.Now()
+	ka.attempts++
+}
+
+// GetAddress returns a candidate address to dial, chosen at random with
+// probability weighted by KnownAddress.Chance. It returns nil if the
+// manager has no addresses, and also when no candidate is accepted
+// within twice as many sampling rounds as there are known addresses.
+func (a *AddrManager) GetAddress() *KnownAddress {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.addrIndex) == 0 {
+		return nil
+	}
+
+	// Rejection sampling: pick a random known address and accept it
+	// with probability equal to its Chance(). This favors addresses
+	// that have not failed or been tried recently without requiring a
+	// full weighted index.
+	candidates := make([]*KnownAddress, 0, len(a.addrIndex))
+	for _, ka := range a.addrIndex {
+		candidates = append(candidates, ka)
+	}
+
+	for attempt := 0; attempt < len(candidates)*2; attempt++ {
+		ka := candidates[rand.Intn(len(candidates))]
+		if rand.Float64() < ka.Chance() {
+			return ka
+		}
+	}
+
+	return nil
+}
+
+// prune removes addresses that have never connected and have not been
+// seen in roughly 30 days, per KnownAddress.isBad.
+func (a *AddrManager) prune() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for k, ka := range a.addrIndex {
+		if !ka.isBad() {
+			continue
+		}
+
+		delete(a.addrIndex, k)
+		for _, bucket := range a.addrNew {
+			delete(bucket, k)
+		}
+		for _, bucket := range a.addrTried {
+			delete(bucket, k)
+		}
+	}
+}
+
+// newBucket picks one of the new buckets by hashing an address's IP
+// together with its source's IP. Addresses are not grouped by network
+// range, so the addresses a single source reports are spread across all
+// of the new buckets rather than confined to a few of them.
+func (a *AddrManager) newBucket(na, src *wire.NetAddress) int {
+	h := fnv32(na.IP.String() + src.IP.String())
+	return int(h % newBucketCount)
+}
+
+// triedBucket picks one of the tried buckets by hashing an address's IP.
+func (a *AddrManager) triedBucket(na *wire.NetAddress) int {
+	h := fnv32(na.IP.String())
+	return int(h % triedBucketCount)
+}
+
+// fnv32 is a small, dependency-free string hash used only to spread
+// addresses across buckets; it has no cryptographic properties.
+func fnv32(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		h *= prime32
+		h ^= uint32(s[i])
+	}
+	return h
+}
+
+// persistedAddress is the on-disk representation of a KnownAddress.
+type persistedAddress struct {
+	Addr        *wire.NetAddress `json:"addr"`
+	Src         *wire.NetAddress `json:"src"`
+	Attempts    int              `json:"attempts"`
+	LastAttempt time.Time        `json:"last_attempt"`
+	LastSuccess time.Time        `json:"last_success"`
+	Tried       bool             `json:"tried"`
+}
+
+func (a *AddrManager) serializePeers() error {
+	peers := make([]persistedAddress, 0, len(a.addrIndex))
+	for _, ka := range a.addrIndex {
+		peers = append(peers, persistedAddress{
+			Addr:        ka.na,
+			Src:         ka.srcAddr,
+			Attempts:    ka.attempts,
+			LastAttempt: ka.lastAttempt,
+			LastSuccess: ka.lastSuccess,
+			Tried:       ka.tried,
+		})
+	}
+
+	b, err := json.Marshal(peers)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(a.dataDir, 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(a.dataDir, peersFilename), b, 0o600)
+}
+
+func (a *AddrManager) deserializePeers() error {
+	path := filepath.Join(a.dataDir, peersFilename)
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var peers []persistedAddress
+	if err := json.Unmarshal(b, &peers); err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		ka := &KnownAddress{
+			na:          p.Addr,
+			srcAddr:     p.Src,
+			attempts:    p.Attempts,
+			lastAttempt: p.LastAttempt,
+			lastSuccess: p.LastSuccess,
+			tried:       p.Tried,
+		}
+
+		k := key(ka.na)
+		a.addrIndex[k] = ka
+		if ka.tried {
+			a.addrTried[a.triedBucket(ka.na)][k] = ka
+		} else {
+			a.addrNew[a.newBucket(ka.na, ka.srcAddr)][k] = ka
+			ka.refs++
+		}
+	}
+
+	return nil
+}
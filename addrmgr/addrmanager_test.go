@@ -0,0 +1,58 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/wire"
+)
+
+func TestPruneRemovesStaleNeverConnectedAddress(t *testing.T) {
+	am := New(t.TempDir())
+
+	stale := wire.NewNetAddress(&net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 8767}, 0)
+	stale.Timestamp = time.Now().Add(-numMissingDays * 24 * time.Hour * 2)
+	src := wire.NewNetAddress(&net.TCPAddr{IP: net.ParseIP("5.6.7.8"), Port: 8767}, 0)
+
+	am.AddAddress(stale, src)
+	if _, ok := am.addrIndex[key(stale)]; !ok {
+		t.Fatal("expected the address to be indexed before pruning")
+	}
+
+	am.prune()
+
+	if _, ok := am.addrIndex[key(stale)]; ok {
+		t.Error("expected prune to remove a never-connected, 30+ day old address")
+	}
+}
+
+func TestStartStopRunsPruneLoopAndCleansUp(t *testing.T) {
+	am := New(t.TempDir())
+
+	stale := wire.NewNetAddress(&net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 8767}, 0)
+	stale.Timestamp = time.Now().Add(-numMissingDays * 24 * time.Hour * 2)
+	src := wire.NewNetAddress(&net.TCPAddr{IP: net.ParseIP("5.6.7.8"), Port: 8767}, 0)
+	am.AddAddress(stale, src)
+
+	if err := am.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	// Call prune, the work the background loop does on each tick,
+	// directly rather than waiting out a real pruneInterval tick.
+	am.prune()
+
+	if err := am.Stop(); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	if _, ok := am.addrIndex[key(stale)]; ok {
+		t.Error("expected the stale address to have been pruned")
+	}
+}
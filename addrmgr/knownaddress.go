@@ -0,0 +1,75 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"math"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/wire"
+)
+
+// KnownAddress tracks information about a known network address that is
+// used to determine how viable an address is as a peer candidate.
+type KnownAddress struct {
+	na          *wire.NetAddress
+	srcAddr     *wire.NetAddress
+	attempts    int
+	lastAttempt time.Time
+	lastSuccess time.Time
+	tried       bool
+	refs        int // reference count of new buckets
+}
+
+// NetAddress returns the underlying network address.
+func (ka *KnownAddress) NetAddress() *wire.NetAddress {
+	return ka.na
+}
+
+// LastAttempt returns the last time the address was attempted.
+func (ka *KnownAddress) LastAttempt() time.Time {
+	return ka.lastAttempt
+}
+
+// Chance returns the selection probability for a known address, in the
+// range [0, 1]. It mirrors the scoring rule popularized by btcd's
+// addrmgr: addresses that were attempted very recently or that keep
+// failing are exponentially less likely to be picked.
+func (ka *KnownAddress) Chance() float64 {
+	if ka.lastAttempt.After(time.Now()) {
+		return 0
+	}
+
+	c := 1.0
+
+	if time.Since(ka.lastAttempt) < 10*time.Minute {
+		c *= 0.01
+	}
+
+	// Cap the failed-attempts penalty at 8 so a repeatedly-failing
+	// address is deprioritized but never fully written off.
+	failures := ka.attempts
+	if failures > 8 {
+		failures = 8
+	}
+	c *= math.Pow(0.66, float64(failures))
+
+	return c
+}
+
+// isBad reports whether a known address should be pruned from the
+// manager entirely: it has never connected and has not been seen in
+// roughly 30 days.
+func (ka *KnownAddress) isBad() bool {
+	if ka.lastSuccess.IsZero() && ka.na.Timestamp.Before(time.Now().Add(-numMissingDays*time.Hour*24)) {
+		return true
+	}
+	return false
+}
+
+// numMissingDays is how long, in days, an address that has never been
+// successfully connected to is kept before being pruned.
+const numMissingDays = 30
@@ -0,0 +1,64 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestKnownAddressChance(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		ka       KnownAddress
+		wantZero bool
+		want     float64
+	}{
+		{
+			name: "never attempted",
+			ka:   KnownAddress{},
+			want: 1.0,
+		},
+		{
+			name:     "future last attempt",
+			ka:       KnownAddress{lastAttempt: now.Add(time.Hour)},
+			wantZero: true,
+		},
+		{
+			name: "attempted 1 minute ago",
+			ka:   KnownAddress{lastAttempt: now.Add(-time.Minute)},
+			want: 0.01,
+		},
+		{
+			name: "attempted 1 hour ago, 3 failures",
+			ka:   KnownAddress{lastAttempt: now.Add(-time.Hour), attempts: 3},
+			want: math.Pow(0.66, 3),
+		},
+		{
+			name: "failures capped at 8",
+			ka:   KnownAddress{lastAttempt: now.Add(-time.Hour), attempts: 20},
+			want: math.Pow(0.66, 8),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.ka.Chance()
+			if test.wantZero {
+				if got != 0 {
+					t.Errorf("Chance() = %v, want 0", got)
+				}
+				return
+			}
+			if math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("Chance() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2020 Coinbase
+// Use of this source code is governed by an Apache-2.0
+// license that can be found in the LICENSE file.
+
+// Package configuration loads and exposes the runtime configuration of
+// the rosetta-ravencoin node: which network it serves and the
+// per-network parameters that affect how requests are answered.
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Mode is the setting that determines if the implementation is "online"
+// or "offline".
+type Mode string
+
+const (
+	// Online is when the implementation is permitted to make outbound
+	// connections.
+	Online Mode = "ONLINE"
+
+	// Offline is when the implementation is not permitted to make
+	// outbound connections.
+	Offline Mode = "OFFLINE"
+
+	// Mainnet is the Ravencoin mainnet.
+	Mainnet string = "MAINNET"
+
+	// Testnet is the Ravencoin testnet.
+	Testnet string = "TESTNET"
+
+	// defaultSafeConfirmations is how many blocks back from the tip the
+	// "safe" block tag resolves to when a network has no override.
+	defaultSafeConfirmations = 6
+
+	// defaultFinalizedConfirmations is how many blocks back from the
+	// tip the "finalized" block tag resolves to when a network has no
+	// override. It is deliberately deep enough that a reorg past this
+	// point would be considered catastrophic.
+	defaultFinalizedConfirmations = 100
+)
+
+// Configuration holds the configuration of the rosetta-ravencoin node.
+type Configuration struct {
+	Mode    Mode
+	Network *types.NetworkIdentifier
+
+	// SafeConfirmations and FinalizedConfirmations are how many blocks
+	// back from the current tip the "safe" (index -1) and "finalized"
+	// (index -2) PartialBlockIdentifier sentinels resolve to. They are
+	// configurable per network because confirmation depth required for
+	// safety scales with a chain's hash rate and reorg history.
+	SafeConfirmations      int64
+	FinalizedConfirmations int64
+
+	// Logging selects which sinks the services layer's structured
+	// audit logger writes to.
+	Logging *LoggingConfiguration
+}
+
+// LoggingConfiguration selects and configures the audit logging sinks
+// the services layer writes to. See the logging package for the hooks
+// themselves.
+type LoggingConfiguration struct {
+	// Sinks is the set of hooks to enable: any of "stdout", "file", and
+	// "syslog".
+	Sinks []string
+
+	// FilePath is where the "file" sink writes, if enabled.
+	FilePath string
+
+	// SyslogNetwork and SyslogAddress are where the "syslog" sink
+	// dials, if enabled (e.g. "udp" and "localhost:514").
+	SyslogNetwork string
+	SyslogAddress string
+}
+
+// HasSink reports whether sink is one of the enabled Sinks.
+func (l *LoggingConfiguration) HasSink(sink string) bool {
+	if l == nil {
+		return false
+	}
+	for _, s := range l.Sinks {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// loadLoggingConfiguration reads the LOG_SINKS (comma-separated),
+// LOG_FILE_PATH, SYSLOG_NETWORK, and SYSLOG_ADDRESS environment
+// variables into a LoggingConfiguration. An unset LOG_SINKS disables
+// audit logging entirely.
+func loadLoggingConfiguration() *LoggingConfiguration {
+	raw := os.Getenv("LOG_SINKS")
+	if raw == "" {
+		return &LoggingConfiguration{}
+	}
+
+	sinks := strings.Split(raw, ",")
+	for i := range sinks {
+		sinks[i] = strings.TrimSpace(sinks[i])
+	}
+
+	return &LoggingConfiguration{
+		Sinks:         sinks,
+		FilePath:      os.Getenv("LOG_FILE_PATH"),
+		SyslogNetwork: os.Getenv("SYSLOG_NETWORK"),
+		SyslogAddress: os.Getenv("SYSLOG_ADDRESS"),
+	}
+}
+
+// networkConfirmations are the confirmation depths for each network
+// rosetta-ravencoin supports. LoadConfiguration copies them as they are;
+// it reads no override from the environment.
+var networkConfirmations = map[string]struct {
+	safe      int64
+	finalized int64
+}{
+	Mainnet: {safe: defaultSafeConfirmations, finalized: defaultFinalizedConfirmations},
+	Testnet: {safe: defaultSafeConfirmations, finalized: defaultSafeConfirmations},
+}
+
+// LoadConfiguration returns the Configuration for the given network,
+// populating SafeConfirmations/FinalizedConfirmations from
+// networkConfirmations.
+func LoadConfiguration(mode Mode, network *types.NetworkIdentifier) (*Configuration, error) {
+	confs, ok := networkConfirmations[network.Network]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a supported network", network.Network)
+	}
+
+	return &Configuration{
+		Mode:                   mode,
+		Network:                network,
+		SafeConfirmations:      confs.safe,
+		FinalizedConfirmations: confs.finalized,
+		Logging:                loadLoggingConfiguration(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileHook appends every Entry, as a line of JSON, to a file, rotating
+// it once it grows past maxBytes.
+type FileHook struct {
+	queue      *boundedQueue
+	path       string
+	maxBytes   int64
+	maxBackups int
+}
+
+// NewFileHook returns a FileHook writing to path, rotating it once it
+// exceeds maxBytes and keeping up to maxBackups rotated copies. Its
+// internal queue holds at most bufferSize entries before the oldest is
+// dropped.
+func NewFileHook(path string, maxBytes int64, maxBackups, bufferSize int) *FileHook {
+	h := &FileHook{
+		queue:      newBoundedQueue(bufferSize),
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+	go h.run()
+	return h
+}
+
+// Fire implements Hook.
+func (h *FileHook) Fire(e Entry) {
+	h.queue.push(e)
+}
+
+// Close implements Hook.
+func (h *FileHook) Close() error {
+	h.queue.close()
+	return nil
+}
+
+func (h *FileHook) run() {
+	for {
+		select {
+		case e := <-h.queue.entries:
+			if err := h.write(e); err != nil {
+				// There is no lower-level sink to report a write
+				// failure to; drop the entry and keep serving the
+				// queue so one bad write does not wedge the hook.
+				continue
+			}
+		case <-h.queue.done:
+			return
+		}
+	}
+}
+
+func (h *FileHook) write(e Entry) error {
+	if err := h.rotateIfNeeded(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := json.Marshal(toEntryJSON(e))
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	_, err = f.Write(b)
+	return err
+}
+
+func (h *FileHook) rotateIfNeeded() error {
+	info, err := os.Stat(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < h.maxBytes {
+		return nil
+	}
+
+	for i := h.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", h.path, i)
+		dst := fmt.Sprintf("%s.%d", h.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+
+	if h.maxBackups > 0 {
+		os.Rename(h.path, fmt.Sprintf("%s.1", h.path))
+	} else {
+		os.Remove(h.path)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package logging provides a structured, levelled logger for the
+// services layer, suitable for offline auditing of every request an
+// Indexer or API service handles. Output is sent to one or more
+// pluggable Hooks (stdout JSON, a rotating file, syslog, ...), following
+// the hook pattern popularized by logrus's syslog hook. Every hook is
+// non-blocking: a slow sink cannot stall block indexing because Fire
+// only ever enqueues onto a bounded, per-hook queue.
+package logging
+
+import "time"
+
+// Level is the severity of a log Entry.
+type Level string
+
+// Supported levels, ordered from least to most severe.
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+// Fields are arbitrary structured attributes attached to a log Entry,
+// e.g. {"method": "GetBalance", "account": "...", "latency_ms": 12}.
+type Fields map[string]interface{}
+
+// Entry is a single structured log record.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+}
+
+// Hook receives every log Entry a Logger fires and is responsible for
+// delivering it to one sink. Fire must not block the caller.
+type Hook interface {
+	Fire(Entry)
+	Close() error
+}
+
+// Logger is a structured, levelled logger that fans every Entry out to
+// its configured Hooks.
+type Logger struct {
+	hooks  []Hook
+	fields Fields
+}
+
+// New returns a Logger that fires every Entry to each of hooks.
+func New(hooks ...Hook) *Logger {
+	return &Logger{hooks: hooks}
+}
+
+// WithFields returns a child Logger that attaches f to every Entry it
+// fires, in addition to any fields attached by its parent.
+func (l *Logger) WithFields(f Fields) *Logger {
+	return &Logger{hooks: l.hooks, fields: merge(l.fields, f)}
+}
+
+// Debug fires a debug-level Entry.
+func (l *Logger) Debug(message string, f Fields) { l.log(LevelDebug, message, f) }
+
+// Info fires an info-level Entry.
+func (l *Logger) Info(message string, f Fields) { l.log(LevelInfo, message, f) }
+
+// Warn fires a warn-level Entry.
+func (l *Logger) Warn(message string, f Fields) { l.log(LevelWarn, message, f) }
+
+// Error fires an error-level Entry.
+func (l *Logger) Error(message string, f Fields) { l.log(LevelError, message, f) }
+
+func (l *Logger) log(level Level, message string, f Fields) {
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  merge(l.fields, f),
+	}
+	for _, h := range l.hooks {
+		h.Fire(entry)
+	}
+}
+
+// Close closes every configured Hook, releasing any resources it holds.
+// Entries still queued in a hook when it is closed are not written.
+func (l *Logger) Close() error {
+	var firstErr error
+	for _, h := range l.hooks {
+		if err := h.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func merge(base, extra Fields) Fields {
+	if len(base) == 0 && len(extra) == 0 {
+		return nil
+	}
+
+	merged := make(Fields, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
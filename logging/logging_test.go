@@ -0,0 +1,61 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import "testing"
+
+type testHook struct {
+	entries []Entry
+}
+
+func (h *testHook) Fire(e Entry) { h.entries = append(h.entries, e) }
+func (h *testHook) Close() error { return nil }
+
+func TestLoggerFiresToEveryHook(t *testing.T) {
+	a, b := &testHook{}, &testHook{}
+	logger := New(a, b)
+
+	logger.Info("hello", Fields{"k": "v"})
+
+	for _, h := range []*testHook{a, b} {
+		if len(h.entries) != 1 {
+			t.Fatalf("expected 1 entry, got %d", len(h.entries))
+		}
+		if h.entries[0].Level != LevelInfo || h.entries[0].Message != "hello" {
+			t.Errorf("unexpected entry: %+v", h.entries[0])
+		}
+		if h.entries[0].Fields["k"] != "v" {
+			t.Errorf("expected field k=v, got %v", h.entries[0].Fields)
+		}
+	}
+}
+
+func TestWithFieldsMerges(t *testing.T) {
+	h := &testHook{}
+	logger := New(h).WithFields(Fields{"request_id": "abc"})
+
+	logger.Warn("slow", Fields{"latency_ms": 42})
+
+	if len(h.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(h.entries))
+	}
+	fields := h.entries[0].Fields
+	if fields["request_id"] != "abc" || fields["latency_ms"] != 42 {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+}
+
+func TestBoundedQueueDropsOldest(t *testing.T) {
+	q := newBoundedQueue(2)
+	q.push(Entry{Message: "1"})
+	q.push(Entry{Message: "2"})
+	q.push(Entry{Message: "3"}) // queue full at "1","2"; "1" should be dropped
+
+	first := <-q.entries
+	second := <-q.entries
+	if first.Message != "2" || second.Message != "3" {
+		t.Errorf("got %q, %q; want 2, 3", first.Message, second.Message)
+	}
+}
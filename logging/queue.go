@@ -0,0 +1,58 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import "sync"
+
+// boundedQueue is a fixed-capacity, drop-oldest queue of Entry values,
+// shared by every Hook implementation in this package so a slow sink
+// degrades by losing its oldest backlog rather than blocking Fire.
+type boundedQueue struct {
+	mu      sync.Mutex
+	entries chan Entry
+	done    chan struct{}
+	closed  bool
+}
+
+func newBoundedQueue(capacity int) *boundedQueue {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &boundedQueue{
+		entries: make(chan Entry, capacity),
+		done:    make(chan struct{}),
+	}
+}
+
+// push enqueues e, dropping the oldest queued entry first if the queue
+// is full.
+func (q *boundedQueue) push(e Entry) {
+	select {
+	case q.entries <- e:
+		return
+	default:
+	}
+
+	select {
+	case <-q.entries:
+	default:
+	}
+
+	select {
+	case q.entries <- e:
+	default:
+	}
+}
+
+// close signals consumers to stop; it is safe to call more than once.
+func (q *boundedQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.done)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// StdoutHook writes every Entry to stdout as a single line of JSON.
+type StdoutHook struct {
+	queue *boundedQueue
+}
+
+// NewStdoutHook returns a StdoutHook whose internal queue holds at most
+// bufferSize entries before the oldest is dropped.
+func NewStdoutHook(bufferSize int) *StdoutHook {
+	h := &StdoutHook{queue: newBoundedQueue(bufferSize)}
+	go h.run()
+	return h
+}
+
+// Fire implements Hook.
+func (h *StdoutHook) Fire(e Entry) {
+	h.queue.push(e)
+}
+
+// Close implements Hook.
+func (h *StdoutHook) Close() error {
+	h.queue.close()
+	return nil
+}
+
+func (h *StdoutHook) run() {
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		select {
+		case e := <-h.queue.entries:
+			_ = enc.Encode(toEntryJSON(e))
+		case <-h.queue.done:
+			return
+		}
+	}
+}
+
+// entryJSON is the wire shape an Entry is serialized as by every JSON
+// sink in this package.
+type entryJSON struct {
+	Time    string `json:"time"`
+	Level   Level  `json:"level"`
+	Message string `json:"msg"`
+	Fields  Fields `json:"fields,omitempty"`
+}
+
+func toEntryJSON(e Entry) entryJSON {
+	return entryJSON{
+		Time:    e.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:   e.Level,
+		Message: e.Message,
+		Fields:  e.Fields,
+	}
+}
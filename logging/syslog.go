@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// SyslogHook delivers every Entry to a syslog collector, framed per
+// RFC 5424 with the structured Entry encoded as JSON in the message
+// part, so any RFC 5424 compliant collector (including journald's
+// syslog bridge) can ingest it.
+type SyslogHook struct {
+	queue   *boundedQueue
+	appName string
+	conn    net.Conn
+}
+
+// NewSyslogHook dials a syslog collector at addr over network (e.g.
+// "udp" or "tcp") and returns a hook that streams every Entry to it.
+// Its internal queue holds at most bufferSize entries before the oldest
+// is dropped.
+func NewSyslogHook(network, addr, appName string, bufferSize int) (*SyslogHook, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("logging: unable to dial syslog at %s: %w", addr, err)
+	}
+
+	h := &SyslogHook{
+		queue:   newBoundedQueue(bufferSize),
+		appName: appName,
+		conn:    conn,
+	}
+	go h.run()
+
+	return h, nil
+}
+
+// Fire implements Hook.
+func (h *SyslogHook) Fire(e Entry) {
+	h.queue.push(e)
+}
+
+// Close implements Hook.
+func (h *SyslogHook) Close() error {
+	h.queue.close()
+	return h.conn.Close()
+}
+
+func (h *SyslogHook) run() {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	pid := os.Getpid()
+
+	for {
+		select {
+		case e := <-h.queue.entries:
+			msg, err := json.Marshal(toEntryJSON(e))
+			if err != nil {
+				continue
+			}
+
+			frame := fmt.Sprintf(
+				"<%d>1 %s %s %s %d - - %s\n",
+				syslogPriority(e.Level),
+				e.Time.UTC().Format(time.RFC3339),
+				hostname,
+				h.appName,
+				pid,
+				msg,
+			)
+			_, _ = h.conn.Write([]byte(frame))
+		case <-h.queue.done:
+			return
+		}
+	}
+}
+
+// syslogPriority maps a Level onto an RFC 5424 PRI value, using the
+// "user-level messages" facility (1) for every entry.
+func syslogPriority(level Level) int {
+	const facility = 1
+
+	severity, ok := map[Level]int{
+		LevelDebug: 7,
+		LevelInfo:  6,
+		LevelWarn:  4,
+		LevelError: 3,
+	}[level]
+	if !ok {
+		severity = 6
+	}
+
+	return facility*8 + severity
+}
@@ -16,6 +16,29 @@ type Indexer struct {
 	mock.Mock
 }
 
+// GetAssetMetadata provides a mock function with given fields: _a0, _a1
+func (_m *Indexer) GetAssetMetadata(_a0 context.Context, _a1 string) (*ravencoin.AssetMetadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.AssetMetadata
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.AssetMetadata); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.AssetMetadata)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Indexer) GetBalance(_a0 context.Context, _a1 *types.AccountIdentifier, _a2 *types.Currency, _a3 *types.PartialBlockIdentifier) (*types.Amount, *types.BlockIdentifier, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -126,6 +149,29 @@ func (_m *Indexer) GetCoins(_a0 context.Context, _a1 *types.AccountIdentifier) (
 	return r0, r1, r2
 }
 
+// GetFinalizedBlock provides a mock function with given fields: _a0
+func (_m *Indexer) GetFinalizedBlock(_a0 context.Context) (*types.BlockIdentifier, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *types.BlockIdentifier
+	if rf, ok := ret.Get(0).(func(context.Context) *types.BlockIdentifier); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.BlockIdentifier)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetScriptPubKeys provides a mock function with given fields: _a0, _a1
 func (_m *Indexer) GetScriptPubKeys(_a0 context.Context, _a1 []*types.Coin) ([]*ravencoin.ScriptPubKey, error) {
 	ret := _m.Called(_a0, _a1)
@@ -148,3 +194,26 @@ func (_m *Indexer) GetScriptPubKeys(_a0 context.Context, _a1 []*types.Coin) ([]*
 
 	return r0, r1
 }
+
+// GetSafeBlock provides a mock function with given fields: _a0
+func (_m *Indexer) GetSafeBlock(_a0 context.Context) (*types.BlockIdentifier, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *types.BlockIdentifier
+	if rf, ok := ret.Get(0).(func(context.Context) *types.BlockIdentifier); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.BlockIdentifier)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
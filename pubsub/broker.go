@@ -0,0 +1,127 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package pubsub fans block, mempool, and account activity events out to
+// many subscribers without letting a slow subscriber stall the
+// publisher: each subscriber has a bounded buffer and, once full, the
+// oldest queued event is dropped to make room for the newest one.
+package pubsub
+
+import "sync"
+
+// Event is a single published notification. Topic identifies what kind
+// of event it is (e.g. "newHeads"); Payload is the event-specific body.
+type Event struct {
+	Topic   string
+	Payload interface{}
+}
+
+// Filter decides whether a subscriber should receive a given event. It
+// is evaluated on the publishing goroutine, so it must not block.
+type Filter func(Event) bool
+
+// subscription is one subscriber's mailbox.
+type subscription struct {
+	mu     sync.Mutex
+	topic  string
+	filter Filter
+	ch     chan Event
+}
+
+// Broker fans events out to subscribers keyed by subscription id.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]*subscription
+	bufferSize  int
+}
+
+// NewBroker returns a Broker whose per-subscriber buffer holds at most
+// bufferSize events before it starts dropping the oldest one.
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &Broker{
+		subscribers: make(map[string]*subscription),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber for topic and returns the channel
+// it should read events from. If filter is non-nil, only events for
+// which it returns true are delivered.
+func (b *Broker) Subscribe(id, topic string, filter Filter) <-chan Event {
+	sub := &subscription{
+		topic:  topic,
+		filter: filter,
+		ch:     make(chan Event, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	return sub.ch
+}
+
+// Unsubscribe removes a subscriber and closes its channel. It is safe to
+// call more than once.
+func (b *Broker) Unsubscribe(id string) {
+	b.mu.Lock()
+	sub, ok := b.subscribers[id]
+	if ok {
+		delete(b.subscribers, id)
+	}
+	b.mu.Unlock()
+
+	if ok {
+		sub.mu.Lock()
+		close(sub.ch)
+		sub.mu.Unlock()
+	}
+}
+
+// Publish delivers an event to every subscriber of topic whose filter
+// (if any) accepts it. A subscriber whose buffer is full has its oldest
+// queued event dropped to make room, so Publish never blocks on a slow
+// reader.
+func (b *Broker) Publish(topic string, payload interface{}) {
+	event := Event{Topic: topic, Payload: payload}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subscribers {
+		if sub.topic != topic {
+			continue
+		}
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+
+		sub.mu.Lock()
+		select {
+		case sub.ch <- event:
+		default:
+			// Buffer full: drop the oldest event and retry once.
+			select {
+			case <-sub.ch:
+			default:
+			}
+			select {
+			case sub.ch <- event:
+			default:
+			}
+		}
+		sub.mu.Unlock()
+	}
+}
+
+// Count returns the number of active subscribers, for diagnostics.
+func (b *Broker) Count() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pubsub
+
+import "testing"
+
+func TestBrokerDeliversMatchingTopic(t *testing.T) {
+	b := NewBroker(4)
+	events := b.Subscribe("sub1", "newHeads", nil)
+
+	b.Publish("newHeads", 1)
+	b.Publish("newMempoolTransactions", "ignored")
+
+	select {
+	case evt := <-events:
+		if evt.Payload != 1 {
+			t.Errorf("wrong payload - got %v, want %v", evt.Payload, 1)
+		}
+	default:
+		t.Fatal("expected an event to be queued")
+	}
+
+	select {
+	case evt := <-events:
+		t.Fatalf("did not expect an event for a different topic, got %v", evt)
+	default:
+	}
+}
+
+func TestBrokerFilter(t *testing.T) {
+	b := NewBroker(4)
+	events := b.Subscribe("sub1", "accountActivity", func(e Event) bool {
+		return e.Payload.(int) > 10
+	})
+
+	b.Publish("accountActivity", 5)
+	b.Publish("accountActivity", 15)
+
+	select {
+	case evt := <-events:
+		if evt.Payload != 15 {
+			t.Errorf("wrong payload - got %v, want %v", evt.Payload, 15)
+		}
+	default:
+		t.Fatal("expected the filtered event to be queued")
+	}
+}
+
+func TestBrokerDropsOldestOnFullBuffer(t *testing.T) {
+	b := NewBroker(2)
+	events := b.Subscribe("sub1", "newHeads", nil)
+
+	b.Publish("newHeads", 1)
+	b.Publish("newHeads", 2)
+	b.Publish("newHeads", 3) // buffer is full at this point; 1 should be dropped
+
+	var got []int
+	for i := 0; i < 2; i++ {
+		got = append(got, (<-events).Payload.(int))
+	}
+
+	if got[0] != 2 || got[1] != 3 {
+		t.Errorf("got %v, want [2 3]", got)
+	}
+}
+
+func TestBrokerUnsubscribe(t *testing.T) {
+	b := NewBroker(2)
+	b.Subscribe("sub1", "newHeads", nil)
+
+	if b.Count() != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", b.Count())
+	}
+
+	b.Unsubscribe("sub1")
+
+	if b.Count() != 0 {
+		t.Fatalf("expected 0 subscribers after Unsubscribe, got %d", b.Count())
+	}
+
+	// Publishing after unsubscribe must not panic.
+	b.Publish("newHeads", 1)
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ravencoin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// OpRVNAsset is the script opcode (OP_RVN_ASSET) Ravencoin Core pushes
+// immediately before the serialized asset payload of an issue, reissue,
+// or transfer output.
+const OpRVNAsset = 0xc0
+
+// AssetScriptType identifies which asset operation an OP_RVN_ASSET
+// payload encodes. The four-byte marker is written verbatim by
+// Ravencoin Core at the start of every asset payload.
+type AssetScriptType string
+
+// Recognized asset script markers.
+const (
+	AssetScriptIssue    AssetScriptType = "rvnq"
+	AssetScriptReissue  AssetScriptType = "rvno"
+	AssetScriptTransfer AssetScriptType = "rvnt"
+)
+
+// AssetScript is the decoded payload of an OP_RVN_ASSET output, covering
+// issuance, reissuance, and transfer of a Ravencoin asset.
+type AssetScript struct {
+	Type AssetScriptType
+	Name string
+
+	// Amount is the number of indivisible asset units moved (transfer)
+	// or created (issue/reissue), denominated the same way as Units
+	// below.
+	Amount int64
+
+	// Units, Reissuable, and IPFSHash are only populated for
+	// AssetScriptIssue and AssetScriptReissue. Units is signed because
+	// a reissuance may carry -1, Ravencoin Core's sentinel for "leave
+	// divisibility unchanged"; an issuance always carries 0-8.
+	Units      int8
+	Reissuable bool
+	IPFSHash   string
+}
+
+// AssetMetadata is the current on-chain state of a Ravencoin asset, as
+// tracked by an Indexer from the issue/reissue/transfer outputs it has
+// processed.
+type AssetMetadata struct {
+	Name          string
+	Units         uint8
+	Reissuable    bool
+	IPFSHash      string
+	TotalSupply   int64
+	IssuanceBlock *types.BlockIdentifier
+}
+
+// AssetCurrency returns the *types.Currency an indexer should use to tag
+// balances and coins for this asset: symbol is the asset name and
+// decimals is its declared divisibility.
+func AssetCurrency(name string, units uint8) *types.Currency {
+	return &types.Currency{
+		Symbol:   name,
+		Decimals: int32(units),
+	}
+}
+
+// ParseAssetScript inspects a scriptPubKey and, if it carries an
+// OP_RVN_ASSET payload, decodes it. ok is false (with a nil error) if the
+// script is an ordinary, non-asset script.
+//
+// The script is tokenized opcode-by-opcode (rather than scanned for the
+// OP_RVN_ASSET byte as a substring) so that a push of arbitrary data -
+// e.g. an ordinary P2PKH output whose 20-byte pubkey hash happens to
+// contain the byte 0xc0 - is skipped as a single push and never
+// mistaken for the opcode.
+func ParseAssetScript(script []byte) (parsed *AssetScript, ok bool, err error) {
+	i := 0
+	for i < len(script) {
+		opcode := script[i]
+
+		if opcode == OpRVNAsset {
+			payload, err := readAssetPushData(script[i+1:])
+			if err != nil {
+				return nil, false, fmt.Errorf("unable to read asset payload: %w", err)
+			}
+			return decodeAssetPayload(payload)
+		}
+
+		advance, ok := pushLength(script[i:])
+		if !ok {
+			// Not a push we know how to skip (or a truncated one): this
+			// is either an ordinary non-push opcode or a malformed
+			// script, neither of which carries an asset payload.
+			i++
+			continue
+		}
+		i += advance
+	}
+
+	return nil, false, nil
+}
+
+// pushLength returns how many bytes, starting at b[0], a push opcode and
+// its payload occupy, so the tokenizer can skip over pushed data without
+// interpreting any of it as an opcode. ok is false if b does not begin
+// with a push opcode, or the push is truncated.
+func pushLength(b []byte) (n int, ok bool) {
+	if len(b) < 1 {
+		return 0, false
+	}
+
+	opcode := b[0]
+	switch {
+	case opcode >= 1 && opcode <= 75:
+		if len(b) < 1+int(opcode) {
+			return 0, false
+		}
+		return 1 + int(opcode), true
+	case opcode == 0x4c: // OP_PUSHDATA1
+		if len(b) < 2 {
+			return 0, false
+		}
+		n := int(b[1])
+		if len(b) < 2+n {
+			return 0, false
+		}
+		return 2 + n, true
+	case opcode == 0x4d: // OP_PUSHDATA2
+		if len(b) < 3 {
+			return 0, false
+		}
+		n := int(b[1]) | int(b[2])<<8
+		if len(b) < 3+n {
+			return 0, false
+		}
+		return 3 + n, true
+	default:
+		return 0, false
+	}
+}
+
+// decodeAssetPayload decodes the payload of an OP_RVN_ASSET push: a
+// four-byte type marker followed by a type-specific body.
+func decodeAssetPayload(payload []byte) (*AssetScript, bool, error) {
+	if len(payload) < 4 {
+		return nil, false, errors.New("asset payload shorter than marker")
+	}
+
+	as := &AssetScript{Type: AssetScriptType(payload[:4])}
+	body := payload[4:]
+
+	switch as.Type {
+	case AssetScriptIssue, AssetScriptReissue:
+		if err := as.unserializeIssuance(body); err != nil {
+			return nil, false, fmt.Errorf("unable to decode %s: %w", as.Type, err)
+		}
+	case AssetScriptTransfer:
+		if err := as.unserializeTransfer(body); err != nil {
+			return nil, false, fmt.Errorf("unable to decode transfer: %w", err)
+		}
+	default:
+		return nil, false, fmt.Errorf("unrecognized asset marker %q", payload[:4])
+	}
+
+	return as, true, nil
+}
+
+// unserializeIssuance decodes the body of a CNewAsset or CReissueAsset,
+// which share the same layout: a length-prefixed name, an 8-byte
+// little-endian amount, a 1-byte signed divisibility, a 1-byte
+// reissuable flag, and an optional IPFS hash. For a reissuance, Units
+// may be -1, Ravencoin Core's sentinel meaning "leave divisibility as
+// it is" rather than an actual unit count.
+func (a *AssetScript) unserializeIssuance(body []byte) error {
+	name, rest, err := readAssetName(body)
+	if err != nil {
+		return err
+	}
+	a.Name = name
+
+	if len(rest) < 8 {
+		return errors.New("truncated amount")
+	}
+	a.Amount = int64(readLE64(rest[:8]))
+	rest = rest[8:]
+
+	if len(rest) < 2 {
+		return errors.New("truncated units/reissuable")
+	}
+	a.Units = int8(rest[0])
+	a.Reissuable = rest[1] != 0
+	rest = rest[2:]
+
+	if len(rest) >= 1 && rest[0] != 0 {
+		rest = rest[1:]
+		if len(rest) < ipfsHashLength {
+			return errors.New("truncated ipfs hash")
+		}
+		a.IPFSHash = string(rest[:ipfsHashLength])
+	}
+
+	return nil
+}
+
+// unserializeTransfer decodes the body of a CAssetTransfer: a
+// length-prefixed name followed by an 8-byte little-endian amount.
+func (a *AssetScript) unserializeTransfer(body []byte) error {
+	name, rest, err := readAssetName(body)
+	if err != nil {
+		return err
+	}
+	a.Name = name
+
+	if len(rest) < 8 {
+		return errors.New("truncated amount")
+	}
+	a.Amount = int64(readLE64(rest[:8]))
+
+	return nil
+}
+
+// ipfsHashLength is the fixed size of the compact (multihash, not
+// base58) IPFS hash Ravencoin Core stores inline in issue/reissue
+// payloads.
+const ipfsHashLength = 34
+
+// readAssetName reads a single-byte-length-prefixed asset name, as
+// serialized by Ravencoin Core's ReadWriteAssetName.
+func readAssetName(b []byte) (name string, rest []byte, err error) {
+	if len(b) < 1 {
+		return "", nil, errors.New("truncated asset name length")
+	}
+	n := int(b[0])
+	if len(b) < 1+n {
+		return "", nil, errors.New("truncated asset name")
+	}
+	return string(b[1 : 1+n]), b[1+n:], nil
+}
+
+// readAssetPushData reads the single CScript push that immediately
+// follows OP_RVN_ASSET and returns its payload.
+func readAssetPushData(b []byte) ([]byte, error) {
+	if len(b) < 1 {
+		return nil, errors.New("empty script after OP_RVN_ASSET")
+	}
+
+	opcode := b[0]
+	switch {
+	case opcode >= 1 && opcode <= 75:
+		if len(b) < 1+int(opcode) {
+			return nil, errors.New("truncated direct push")
+		}
+		return b[1 : 1+int(opcode)], nil
+	case opcode == 0x4c: // OP_PUSHDATA1
+		if len(b) < 2 {
+			return nil, errors.New("truncated OP_PUSHDATA1 length")
+		}
+		n := int(b[1])
+		if len(b) < 2+n {
+			return nil, errors.New("truncated OP_PUSHDATA1 payload")
+		}
+		return b[2 : 2+n], nil
+	case opcode == 0x4d: // OP_PUSHDATA2
+		if len(b) < 3 {
+			return nil, errors.New("truncated OP_PUSHDATA2 length")
+		}
+		n := int(b[1]) | int(b[2])<<8
+		if len(b) < 3+n {
+			return nil, errors.New("truncated OP_PUSHDATA2 payload")
+		}
+		return b[3 : 3+n], nil
+	default:
+		return nil, fmt.Errorf("unsupported push opcode 0x%x", opcode)
+	}
+}
+
+func readLE64(b []byte) uint64 {
+	var v uint64
+	for i := 7; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ravencoin
+
+import (
+	"testing"
+)
+
+func appendAssetName(b []byte, name string) []byte {
+	return append(append(b, byte(len(name))), name...)
+}
+
+func appendLE64(b []byte, v int64) []byte {
+	u := uint64(v)
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(u))
+		u >>= 8
+	}
+	return b
+}
+
+func pushData(payload []byte) []byte {
+	return append([]byte{byte(len(payload))}, payload...)
+}
+
+func TestParseAssetScript(t *testing.T) {
+	p2pkhPrefix := []byte{0x76, 0xa9, 0x14}
+	pubKeyHash := make([]byte, 20)
+	p2pkhSuffix := []byte{0x88, 0xac}
+
+	t.Run("issue", func(t *testing.T) {
+		payload := []byte(AssetScriptIssue)
+		payload = appendAssetName(payload, "RAVEN")
+		payload = appendLE64(payload, 100000)
+		payload = append(payload, 0) // units
+		payload = append(payload, 1) // reissuable
+		payload = append(payload, 0) // no IPFS hash
+
+		script := append(p2pkhPrefix, pubKeyHash...)
+		script = append(script, p2pkhSuffix...)
+		script = append(script, OpRVNAsset)
+		script = append(script, pushData(payload)...)
+
+		as, ok, err := ParseAssetScript(script)
+		if err != nil {
+			t.Fatalf("ParseAssetScript returned error: %v", err)
+		}
+		if !ok {
+			t.Fatal("ParseAssetScript did not recognize an asset script")
+		}
+		if as.Type != AssetScriptIssue {
+			t.Errorf("wrong type - got %v, want %v", as.Type, AssetScriptIssue)
+		}
+		if as.Name != "RAVEN" {
+			t.Errorf("wrong name - got %v, want %v", as.Name, "RAVEN")
+		}
+		if as.Amount != 100000 {
+			t.Errorf("wrong amount - got %v, want %v", as.Amount, 100000)
+		}
+		if !as.Reissuable {
+			t.Error("expected reissuable to be true")
+		}
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		payload := []byte(AssetScriptTransfer)
+		payload = appendAssetName(payload, "RAVEN")
+		payload = appendLE64(payload, 42)
+
+		script := append(p2pkhPrefix, pubKeyHash...)
+		script = append(script, p2pkhSuffix...)
+		script = append(script, OpRVNAsset)
+		script = append(script, pushData(payload)...)
+
+		as, ok, err := ParseAssetScript(script)
+		if err != nil {
+			t.Fatalf("ParseAssetScript returned error: %v", err)
+		}
+		if !ok {
+			t.Fatal("ParseAssetScript did not recognize an asset script")
+		}
+		if as.Type != AssetScriptTransfer {
+			t.Errorf("wrong type - got %v, want %v", as.Type, AssetScriptTransfer)
+		}
+		if as.Amount != 42 {
+			t.Errorf("wrong amount - got %v, want %v", as.Amount, 42)
+		}
+	})
+
+	t.Run("reissue", func(t *testing.T) {
+		payload := []byte(AssetScriptReissue)
+		payload = appendAssetName(payload, "RAVEN")
+		payload = appendLE64(payload, 500)
+		payload = append(payload, 0xff) // units: -1, meaning "unchanged"
+		payload = append(payload, 0)    // reissuable: false
+		payload = append(payload, 0)    // no IPFS hash
+
+		script := append(p2pkhPrefix, pubKeyHash...)
+		script = append(script, p2pkhSuffix...)
+		script = append(script, OpRVNAsset)
+		script = append(script, pushData(payload)...)
+
+		as, ok, err := ParseAssetScript(script)
+		if err != nil {
+			t.Fatalf("ParseAssetScript returned error: %v", err)
+		}
+		if !ok {
+			t.Fatal("ParseAssetScript did not recognize an asset script")
+		}
+		if as.Type != AssetScriptReissue {
+			t.Errorf("wrong type - got %v, want %v", as.Type, AssetScriptReissue)
+		}
+		if as.Name != "RAVEN" {
+			t.Errorf("wrong name - got %v, want %v", as.Name, "RAVEN")
+		}
+		if as.Amount != 500 {
+			t.Errorf("wrong amount - got %v, want %v", as.Amount, 500)
+		}
+		if as.Units != -1 {
+			t.Errorf("wrong units - got %v, want %v", as.Units, -1)
+		}
+		if as.Reissuable {
+			t.Error("expected reissuable to be false")
+		}
+	})
+
+	t.Run("not an asset script", func(t *testing.T) {
+		script := append(p2pkhPrefix, pubKeyHash...)
+		script = append(script, p2pkhSuffix...)
+
+		_, ok, err := ParseAssetScript(script)
+		if err != nil {
+			t.Fatalf("ParseAssetScript returned error: %v", err)
+		}
+		if ok {
+			t.Error("ParseAssetScript unexpectedly recognized a plain script")
+		}
+	})
+
+	t.Run("pubkey hash containing the asset opcode byte is not mistaken for one", func(t *testing.T) {
+		hashWithOpcodeByte := make([]byte, 20)
+		hashWithOpcodeByte[7] = OpRVNAsset // 0xc0, embedded in ordinary push data
+
+		script := append(append([]byte{}, p2pkhPrefix...), hashWithOpcodeByte...)
+		script = append(script, p2pkhSuffix...)
+
+		as, ok, err := ParseAssetScript(script)
+		if err != nil {
+			t.Fatalf("ParseAssetScript returned error: %v", err)
+		}
+		if ok {
+			t.Errorf("ParseAssetScript mistook a pubkey hash byte for OP_RVN_ASSET: %+v", as)
+		}
+	})
+}
+
+func TestAssetCurrency(t *testing.T) {
+	currency := AssetCurrency("RAVEN", 4)
+	if currency.Symbol != "RAVEN" {
+		t.Errorf("wrong symbol - got %v, want %v", currency.Symbol, "RAVEN")
+	}
+	if currency.Decimals != 4 {
+		t.Errorf("wrong decimals - got %v, want %v", currency.Decimals, 4)
+	}
+}
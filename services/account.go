@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Coinbase
+// Use of this source code is governed by an Apache-2.0
+// license that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// AccountAPIService implements the server.AccountAPIServicer interface.
+type AccountAPIService struct {
+	config *configuration.Configuration
+	i      Indexer
+}
+
+// NewAccountAPIService creates a new AccountAPIService.
+func NewAccountAPIService(config *configuration.Configuration, i Indexer) *AccountAPIService {
+	return &AccountAPIService{
+		config: config,
+		i:      i,
+	}
+}
+
+// Balance implements the /account/balance endpoint. A safe or finalized
+// block tag is resolved to a concrete block before the balance lookup is
+// made, so the caller never races a reorg.
+func (s *AccountAPIService) Balance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+) (*types.AccountBalanceResponse, error) {
+	resolved, err := ResolveBlockIdentifier(ctx, s.i, request.BlockIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := &types.Currency{Symbol: "RVN", Decimals: 8}
+	if len(request.Currencies) > 0 {
+		currency = request.Currencies[0]
+	}
+
+	amount, block, err := s.i.GetBalance(ctx, request.AccountIdentifier, currency, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountBalanceResponse{
+		BlockIdentifier: block,
+		Balances:        []*types.Amount{amount},
+	}, nil
+}
+
+// Coins implements the /account/coins endpoint. Unlike Balance, this
+// cannot be pinned to a safe/finalized block: types.AccountCoinsRequest
+// carries no BlockIdentifier, so there is nothing here to resolve - see
+// Indexer.GetCoins.
+func (s *AccountAPIService) Coins(
+	ctx context.Context,
+	request *types.AccountCoinsRequest,
+) (*types.AccountCoinsResponse, error) {
+	coins, block, err := s.i.GetCoins(ctx, request.AccountIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountCoinsResponse{
+		BlockIdentifier: block,
+		Coins:           coins,
+	}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Coinbase
+// Use of this source code is governed by an Apache-2.0
+// license that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Sentinel PartialBlockIdentifier.Index values that resolve to the
+// chain's safe and finalized tips instead of an explicit height,
+// mirroring the block-tag convention recent Ethereum RPC clients
+// expose. A finalized query pinned to one of these never returns data
+// from a block that is later orphaned, because it stays
+// FinalizedConfirmations blocks behind the tip.
+const (
+	SafeBlockIndex      int64 = -1
+	FinalizedBlockIndex int64 = -2
+)
+
+// BlockAPIService implements the server.BlockAPIServicer interface.
+type BlockAPIService struct {
+	config *configuration.Configuration
+	i      Indexer
+}
+
+// NewBlockAPIService creates a new BlockAPIService.
+func NewBlockAPIService(config *configuration.Configuration, i Indexer) *BlockAPIService {
+	return &BlockAPIService{
+		config: config,
+		i:      i,
+	}
+}
+
+// Block implements the /block endpoint.
+func (s *BlockAPIService) Block(
+	ctx context.Context,
+	request *types.BlockRequest,
+) (*types.BlockResponse, error) {
+	resolved, err := ResolveBlockIdentifier(ctx, s.i, request.BlockIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.i.GetBlockLazy(ctx, resolved)
+}
+
+// ResolveBlockIdentifier rewrites the safe/finalized block-tag sentinels
+// in a PartialBlockIdentifier into the concrete block they currently
+// refer to, so account and block lookups can be pinned to them. Any
+// other PartialBlockIdentifier (an explicit index, hash, or neither) is
+// returned unchanged.
+func ResolveBlockIdentifier(
+	ctx context.Context,
+	i Indexer,
+	partialBlockIdentifier *types.PartialBlockIdentifier,
+) (*types.PartialBlockIdentifier, error) {
+	if partialBlockIdentifier == nil || partialBlockIdentifier.Index == nil {
+		return partialBlockIdentifier, nil
+	}
+
+	var (
+		block *types.BlockIdentifier
+		err   error
+	)
+
+	switch *partialBlockIdentifier.Index {
+	case SafeBlockIndex:
+		block, err = i.GetSafeBlock(ctx)
+	case FinalizedBlockIndex:
+		block, err = i.GetFinalizedBlock(ctx)
+	default:
+		return partialBlockIdentifier, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PartialBlockIdentifier{Index: &block.Index, Hash: &block.Hash}, nil
+}
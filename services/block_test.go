@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Coinbase
+// Use of this source code is governed by an Apache-2.0
+// license that can be found in the LICENSE file.
+
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/services"
+
+	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptr(i int64) *int64      { return &i }
+func strPtr(s string) *string { return &s }
+
+// TestBlock_FinalizedSurvivesReorg ensures that a request for the
+// finalized block tag always resolves through GetFinalizedBlock - which
+// tracks tip - FinalizedConfirmations - and therefore never observes a
+// block that has since been orphaned at the (unfinalized) tip, whether
+// that's queried through /block or through a finalized-pinned
+// /account/balance.
+func TestBlock_FinalizedSurvivesReorg(t *testing.T) {
+	mockIndexer := &mocks.Indexer{}
+	cfg := &configuration.Configuration{
+		Mode:                   configuration.Online,
+		FinalizedConfirmations: 100,
+	}
+	blockSvc := services.NewBlockAPIService(cfg, mockIndexer)
+	accountSvc := services.NewAccountAPIService(cfg, mockIndexer)
+
+	account := &types.AccountIdentifier{Address: "RAddress"}
+	currency := &types.Currency{Symbol: "RVN", Decimals: 8}
+	balance := &types.Amount{Value: "500"}
+
+	// The tip starts at height 1000, so the finalized tag resolves to
+	// tip - FinalizedConfirmations = 900.
+	finalizedBeforeReorg := &types.BlockIdentifier{Index: 900, Hash: "hash-900"}
+	partialBeforeReorg := &types.PartialBlockIdentifier{Index: &finalizedBeforeReorg.Index, Hash: &finalizedBeforeReorg.Hash}
+
+	mockIndexer.On("GetFinalizedBlock", mock.Anything).Return(finalizedBeforeReorg, nil).Twice()
+	mockIndexer.On("GetBlockLazy", mock.Anything, partialBeforeReorg).
+		Return(&types.BlockResponse{Block: &types.Block{BlockIdentifier: finalizedBeforeReorg}}, nil).Once()
+	mockIndexer.On("GetBalance", mock.Anything, account, currency, partialBeforeReorg).
+		Return(balance, finalizedBeforeReorg, nil).Once()
+
+	resp, err := blockSvc.Block(context.Background(), &types.BlockRequest{
+		BlockIdentifier: &types.PartialBlockIdentifier{Index: ptr(services.FinalizedBlockIndex)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, finalizedBeforeReorg, resp.Block.BlockIdentifier)
+
+	balResp, err := accountSvc.Balance(context.Background(), &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		Currencies:        []*types.Currency{currency},
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: ptr(services.FinalizedBlockIndex)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, finalizedBeforeReorg, balResp.BlockIdentifier)
+
+	// The chain now reorgs: the block at height 999 ("orphan-999") is
+	// replaced by a competing block and the tip advances to 1001, moving
+	// the finalized tag forward to 901 on the new canonical chain.
+	finalizedAfterReorg := &types.BlockIdentifier{Index: 901, Hash: "hash-901"}
+	partialAfterReorg := &types.PartialBlockIdentifier{Index: &finalizedAfterReorg.Index, Hash: &finalizedAfterReorg.Hash}
+
+	mockIndexer.On("GetFinalizedBlock", mock.Anything).Return(finalizedAfterReorg, nil).Twice()
+	mockIndexer.On("GetBlockLazy", mock.Anything, partialAfterReorg).
+		Return(&types.BlockResponse{Block: &types.Block{BlockIdentifier: finalizedAfterReorg}}, nil).Once()
+	mockIndexer.On("GetBalance", mock.Anything, account, currency, partialAfterReorg).
+		Return(balance, finalizedAfterReorg, nil).Once()
+
+	resp, err = blockSvc.Block(context.Background(), &types.BlockRequest{
+		BlockIdentifier: &types.PartialBlockIdentifier{Index: ptr(services.FinalizedBlockIndex)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, finalizedAfterReorg, resp.Block.BlockIdentifier)
+
+	balResp, err = accountSvc.Balance(context.Background(), &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		Currencies:        []*types.Currency{currency},
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: ptr(services.FinalizedBlockIndex)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, finalizedAfterReorg, balResp.BlockIdentifier)
+
+	// Neither the /block nor the /account/balance lookup ever resolved to
+	// the orphaned block, before or after the reorg.
+	orphaned := &types.PartialBlockIdentifier{Index: ptr(999), Hash: strPtr("orphan-999")}
+	mockIndexer.AssertNotCalled(t, "GetBlockLazy", mock.Anything, orphaned)
+	mockIndexer.AssertNotCalled(t, "GetBalance", mock.Anything, account, currency, orphaned)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestBlock_ExplicitIdentifierUnresolved(t *testing.T) {
+	mockIndexer := &mocks.Indexer{}
+	cfg := &configuration.Configuration{Mode: configuration.Online}
+	svc := services.NewBlockAPIService(cfg, mockIndexer)
+
+	explicit := &types.PartialBlockIdentifier{Index: ptr(42)}
+	mockIndexer.On("GetBlockLazy", mock.Anything, explicit).Return(&types.BlockResponse{}, nil)
+
+	_, err := svc.Block(context.Background(), &types.BlockRequest{BlockIdentifier: explicit})
+	assert.NoError(t, err)
+	mockIndexer.AssertExpectations(t)
+}
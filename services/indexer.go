@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Coinbase
+// Use of this source code is governed by an Apache-2.0
+// license that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Indexer defines a generic interface to allow different indexers to be
+// used for request processing. Beyond native RVN, an indexer must track
+// every Ravencoin asset (RVN's on-chain issued tokens) as its own
+// *types.Currency so that balances, coins, and metadata can be queried
+// per asset the same way they are for RVN.
+type Indexer interface {
+	GetBlockLazy(ctx context.Context, blockIdentifier *types.PartialBlockIdentifier) (*types.BlockResponse, error)
+	GetBlockTransaction(
+		ctx context.Context,
+		blockIdentifier *types.BlockIdentifier,
+		transactionIdentifier *types.TransactionIdentifier,
+	) (*types.Transaction, error)
+	GetScriptPubKeys(ctx context.Context, coins []*types.Coin) ([]*ravencoin.ScriptPubKey, error)
+
+	// GetCoins returns the set of unspent coins controlled by an account,
+	// across RVN and every asset currency the account holds a UTXO in.
+	//
+	// Unlike GetBalance, GetCoins cannot be pinned to a safe/finalized
+	// block: types.AccountCoinsRequest carries no BlockIdentifier for
+	// AccountAPIService.Coins to resolve and pass down, so this always
+	// answers against the current tip. A client that needs a
+	// reorg-stable coin set should pin GetBalance instead.
+	GetCoins(ctx context.Context, accountIdentifier *types.AccountIdentifier) ([]*types.Coin, *types.BlockIdentifier, error)
+
+	// GetBalance returns the balance of an account in the given currency
+	// at (or nearest before) the provided block. currency may be RVN or
+	// any asset currency returned by GetAssetMetadata.
+	GetBalance(
+		ctx context.Context,
+		accountIdentifier *types.AccountIdentifier,
+		currency *types.Currency,
+		headBlock *types.PartialBlockIdentifier,
+	) (*types.Amount, *types.BlockIdentifier, error)
+
+	// GetAssetMetadata returns the current on-chain state of a Ravencoin
+	// asset: its outstanding supply, whether it can still be reissued,
+	// the IPFS hash attached at issuance (if any), and the block the
+	// asset was issued in.
+	GetAssetMetadata(ctx context.Context, name string) (*ravencoin.AssetMetadata, error)
+
+	// GetSafeBlock returns the block identifier tip - SafeConfirmations
+	// blocks back from the current tip.
+	GetSafeBlock(ctx context.Context) (*types.BlockIdentifier, error)
+
+	// GetFinalizedBlock returns the block identifier
+	// tip - FinalizedConfirmations blocks back from the current tip.
+	GetFinalizedBlock(ctx context.Context) (*types.BlockIdentifier, error)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/logging"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// LoggingIndexer wraps an Indexer, emitting a structured audit entry
+// (method, account, block, latency, result size, and error) for every
+// GetBalance, GetCoins, GetBlockLazy, GetScriptPubKeys, and
+// GetAssetMetadata call. GetBlockTransaction, GetSafeBlock, and
+// GetFinalizedBlock go straight to the wrapped Indexer and are not logged.
+// Every API service in this package (BlockAPIService, AccountAPIService,
+// and, in future chunks, the construction and mempool services) reaches
+// the indexer exclusively through this interface, so wrapping it here
+// covers all of them.
+type LoggingIndexer struct {
+	Indexer
+	logger *logging.Logger
+}
+
+// NewLoggingIndexer returns an Indexer that logs every call to i via
+// logger before returning its result.
+func NewLoggingIndexer(i Indexer, logger *logging.Logger) *LoggingIndexer {
+	return &LoggingIndexer{Indexer: i, logger: logger}
+}
+
+// GetBalance implements Indexer.
+func (l *LoggingIndexer) GetBalance(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	block *types.PartialBlockIdentifier,
+) (*types.Amount, *types.BlockIdentifier, error) {
+	start := time.Now()
+	amount, resultBlock, err := l.Indexer.GetBalance(ctx, account, currency, block)
+	l.logCall("GetBalance", start, logging.Fields{
+		"account":  accountAddress(account),
+		"currency": currencySymbol(currency),
+		"block":    blockIndex(resultBlock),
+	}, 1, err)
+	return amount, resultBlock, err
+}
+
+// GetCoins implements Indexer.
+func (l *LoggingIndexer) GetCoins(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) ([]*types.Coin, *types.BlockIdentifier, error) {
+	start := time.Now()
+	coins, block, err := l.Indexer.GetCoins(ctx, account)
+	l.logCall("GetCoins", start, logging.Fields{
+		"account": accountAddress(account),
+		"block":   blockIndex(block),
+	}, len(coins), err)
+	return coins, block, err
+}
+
+// GetBlockLazy implements Indexer.
+func (l *LoggingIndexer) GetBlockLazy(
+	ctx context.Context,
+	partialBlockIdentifier *types.PartialBlockIdentifier,
+) (*types.BlockResponse, error) {
+	start := time.Now()
+	resp, err := l.Indexer.GetBlockLazy(ctx, partialBlockIdentifier)
+
+	size := 0
+	var block *types.BlockIdentifier
+	if resp != nil && resp.Block != nil {
+		block = resp.Block.BlockIdentifier
+		size = len(resp.Block.Transactions)
+	}
+
+	l.logCall("GetBlockLazy", start, logging.Fields{
+		"block": blockIndex(block),
+	}, size, err)
+	return resp, err
+}
+
+// GetScriptPubKeys implements Indexer.
+func (l *LoggingIndexer) GetScriptPubKeys(
+	ctx context.Context,
+	coins []*types.Coin,
+) ([]*ravencoin.ScriptPubKey, error) {
+	start := time.Now()
+	scriptPubKeys, err := l.Indexer.GetScriptPubKeys(ctx, coins)
+	l.logCall("GetScriptPubKeys", start, logging.Fields{}, len(scriptPubKeys), err)
+	return scriptPubKeys, err
+}
+
+// GetAssetMetadata implements Indexer.
+func (l *LoggingIndexer) GetAssetMetadata(ctx context.Context, name string) (*ravencoin.AssetMetadata, error) {
+	start := time.Now()
+	meta, err := l.Indexer.GetAssetMetadata(ctx, name)
+	l.logCall("GetAssetMetadata", start, logging.Fields{"asset": name}, 1, err)
+	return meta, err
+}
+
+func (l *LoggingIndexer) logCall(method string, start time.Time, fields logging.Fields, resultSize int, err error) {
+	if l.logger == nil {
+		return
+	}
+
+	fields["method"] = method
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	fields["result_size"] = resultSize
+
+	if err != nil {
+		fields["error"] = err.Error()
+		l.logger.Error("indexer request failed", fields)
+		return
+	}
+
+	l.logger.Info("indexer request", fields)
+}
+
+func accountAddress(account *types.AccountIdentifier) string {
+	if account == nil {
+		return ""
+	}
+	return account.Address
+}
+
+func currencySymbol(currency *types.Currency) string {
+	if currency == nil {
+		return ""
+	}
+	return currency.Symbol
+}
+
+func blockIndex(block *types.BlockIdentifier) int64 {
+	if block == nil {
+		return 0
+	}
+	return block.Index
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/logging"
+	"github.com/RavenProject/rosetta-ravencoin/services"
+
+	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// captureHook records every Entry fired to it, so tests can assert on
+// what a Logger observed without standing up a real sink.
+type captureHook struct {
+	entries []logging.Entry
+}
+
+func (h *captureHook) Fire(e logging.Entry) { h.entries = append(h.entries, e) }
+func (h *captureHook) Close() error         { return nil }
+
+func TestLoggingIndexer_GetBalance(t *testing.T) {
+	mockIndexer := &mocks.Indexer{}
+	hook := &captureHook{}
+	logger := logging.New(hook)
+	indexer := services.NewLoggingIndexer(mockIndexer, logger)
+
+	account := &types.AccountIdentifier{Address: "RAddress"}
+	currency := &types.Currency{Symbol: "RVN"}
+	block := &types.BlockIdentifier{Index: 10, Hash: "hash-10"}
+	amount := &types.Amount{Value: "100"}
+
+	mockIndexer.On("GetBalance", mock.Anything, account, currency, (*types.PartialBlockIdentifier)(nil)).
+		Return(amount, block, nil)
+
+	gotAmount, gotBlock, err := indexer.GetBalance(context.Background(), account, currency, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, amount, gotAmount)
+	assert.Equal(t, block, gotBlock)
+
+	if len(hook.entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(hook.entries))
+	}
+	entry := hook.entries[0]
+	assert.Equal(t, logging.LevelInfo, entry.Level)
+	assert.Equal(t, "GetBalance", entry.Fields["method"])
+	assert.Equal(t, "RAddress", entry.Fields["account"])
+	assert.Equal(t, int64(10), entry.Fields["block"])
+}
+
+func TestLoggingIndexer_LogsErrors(t *testing.T) {
+	mockIndexer := &mocks.Indexer{}
+	hook := &captureHook{}
+	logger := logging.New(hook)
+	indexer := services.NewLoggingIndexer(mockIndexer, logger)
+
+	account := &types.AccountIdentifier{Address: "RAddress"}
+
+	mockIndexer.On("GetCoins", mock.Anything, account).
+		Return([]*types.Coin(nil), (*types.BlockIdentifier)(nil), errors.New("boom"))
+
+	_, _, err := indexer.GetCoins(context.Background(), account)
+	assert.EqualError(t, err, "boom")
+
+	if len(hook.entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(hook.entries))
+	}
+	assert.Equal(t, logging.LevelError, hook.entries[0].Level)
+	assert.Equal(t, "boom", hook.entries[0].Fields["error"])
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package subscribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/gorilla/websocket"
+)
+
+// clientBufferSize is how many undelivered events a Client subscription
+// channel holds before the oldest is dropped, mirroring the server-side
+// Broker's backpressure policy.
+const clientBufferSize = 64
+
+// Client is a minimal Go client for the subscription WebSocket API, so
+// wallets and explorers can stop polling /block in a loop.
+type Client struct {
+	conn *websocket.Conn
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan rpcResponse
+	subs    map[string]chan json.RawMessage
+}
+
+// Dial opens a connection to a subscription WebSocket endpoint and
+// starts servicing it.
+func Dial(url string) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe: unable to dial %s: %w", url, err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		pending: make(map[int64]chan rpcResponse),
+		subs:    make(map[string]chan json.RawMessage),
+	}
+	go c.readLoop()
+
+	return c, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Heads subscribes to new blocks accepted onto the best chain.
+func (c *Client) Heads() (string, <-chan *types.BlockIdentifier, error) {
+	id, raw, err := c.subscribe(NewHeads, Filter{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	out := make(chan *types.BlockIdentifier, clientBufferSize)
+	go func() {
+		defer close(out)
+		for r := range raw {
+			var block types.BlockIdentifier
+			if json.Unmarshal(r, &block) == nil {
+				out <- &block
+			}
+		}
+	}()
+
+	return id, out, nil
+}
+
+// MempoolTransactions subscribes to transactions observed in the
+// mempool.
+func (c *Client) MempoolTransactions() (string, <-chan *types.TransactionIdentifier, error) {
+	id, raw, err := c.subscribe(NewMempoolTransactions, Filter{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	out := make(chan *types.TransactionIdentifier, clientBufferSize)
+	go func() {
+		defer close(out)
+		for r := range raw {
+			var tx types.TransactionIdentifier
+			if json.Unmarshal(r, &tx) == nil {
+				out <- &tx
+			}
+		}
+	}()
+
+	return id, out, nil
+}
+
+// AccountActivity subscribes to activity on accounts matching filter.
+func (c *Client) AccountActivity(filter Filter) (string, <-chan *AccountActivityEvent, error) {
+	id, raw, err := c.subscribe(AccountActivity, filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	out := make(chan *AccountActivityEvent, clientBufferSize)
+	go func() {
+		defer close(out)
+		for r := range raw {
+			var evt AccountActivityEvent
+			if json.Unmarshal(r, &evt) == nil {
+				out <- &evt
+			}
+		}
+	}()
+
+	return id, out, nil
+}
+
+// Unsubscribe tears down a subscription created by Heads,
+// MempoolTransactions, or AccountActivity. Closing the channel under c.mu
+// - the same lock readLoop holds while delivering to it - is what keeps a
+// notification in flight from being sent on an already-closed channel.
+func (c *Client) Unsubscribe(id string) error {
+	if _, err := c.call("rosetta_unsubscribe", id); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if ch, ok := c.subs[id]; ok {
+		close(ch)
+		delete(c.subs, id)
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) subscribe(eventType EventType, filter Filter) (string, <-chan json.RawMessage, error) {
+	resp, err := c.call("rosetta_subscribe", eventType, filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, ok := resp.Result.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("subscribe: unexpected subscription id %v", resp.Result)
+	}
+
+	ch := make(chan json.RawMessage, clientBufferSize)
+	c.mu.Lock()
+	c.subs[id] = ch
+	c.mu.Unlock()
+
+	return id, ch, nil
+}
+
+func (c *Client) call(method string, params ...interface{}) (*rpcResponse, error) {
+	id := atomic.AddInt64(&c.nextID, 1)
+
+	rawParams := make([]json.RawMessage, len(params))
+	for i, p := range params {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		rawParams[i] = b
+	}
+
+	respCh := make(chan rpcResponse, 1)
+	c.mu.Lock()
+	c.pending[id] = respCh
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	idRaw, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.conn.WriteJSON(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      idRaw,
+		Method:  method,
+		Params:  rawParams,
+	}); err != nil {
+		return nil, err
+	}
+
+	resp := <-respCh
+	if resp.Error != nil {
+		return nil, fmt.Errorf("subscribe: %s", resp.Error.Message)
+	}
+	return &resp, nil
+}
+
+// readLoop dispatches incoming frames to either a pending call's
+// response channel or a live subscription's event channel.
+func (c *Client) readLoop() {
+	for {
+		var probe struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if json.Unmarshal(data, &probe) != nil {
+			continue
+		}
+
+		if probe.Method == notificationMethod {
+			var note rpcNotification
+			if json.Unmarshal(data, &note) != nil {
+				continue
+			}
+
+			// Hold c.mu across the lookup and the send so Unsubscribe
+			// cannot close ch between the two: close and send are
+			// serialized on the same mutex, so the send never lands on
+			// an already-closed channel.
+			c.mu.Lock()
+			ch, ok := c.subs[note.Params.Subscription]
+			if !ok {
+				c.mu.Unlock()
+				continue
+			}
+
+			select {
+			case ch <- note.Params.Result:
+			default:
+				// Drop the oldest queued result to make room, matching
+				// the server Broker's backpressure policy.
+				select {
+				case <-ch:
+				default:
+				}
+				select {
+				case ch <- note.Params.Result:
+				default:
+				}
+			}
+			c.mu.Unlock()
+			continue
+		}
+
+		var resp rpcResponse
+		if json.Unmarshal(data, &resp) != nil {
+			continue
+		}
+
+		var respID int64
+		if json.Unmarshal(resp.ID, &respID) != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[respID]
+		c.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+}
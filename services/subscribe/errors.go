@@ -0,0 +1,19 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package subscribe
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errMissingEventType      = errors.New("subscribe: params[0] must be an event type")
+	errMissingSubscriptionID = errors.New("subscribe: params[0] must be a subscription id")
+)
+
+func errUnknownMethod(method string) error {
+	return fmt.Errorf("subscribe: unknown method %q", method)
+}
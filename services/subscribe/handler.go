@@ -0,0 +1,171 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package subscribe
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/RavenProject/rosetta-ravencoin/pubsub"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// Handler upgrades incoming HTTP requests to the subscription WebSocket
+// and services rosetta_subscribe/rosetta_unsubscribe requests against a
+// Service, attached to the existing HTTP server alongside the REST
+// Rosetta endpoints.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler returns a Handler backed by service.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var writeMu sync.Mutex
+	subs := make(map[string]bool)
+	defer func() {
+		for id := range subs {
+			h.service.Unsubscribe(id)
+		}
+	}()
+
+	for {
+		var req rpcRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		switch req.Method {
+		case "rosetta_subscribe":
+			id, err := h.subscribe(conn, &writeMu, req)
+			if err != nil {
+				writeError(conn, &writeMu, req.ID, err)
+				continue
+			}
+			subs[id] = true
+		case "rosetta_unsubscribe":
+			id, err := unsubscribeID(req)
+			if err != nil {
+				writeError(conn, &writeMu, req.ID, err)
+				continue
+			}
+			h.service.Unsubscribe(id)
+			delete(subs, id)
+			writeResult(conn, &writeMu, req.ID, true)
+		default:
+			writeError(conn, &writeMu, req.ID, errUnknownMethod(req.Method))
+		}
+	}
+}
+
+// subscribe parses a rosetta_subscribe request, registers it with the
+// Service, and starts forwarding its events to the connection as
+// rosetta_subscription notifications.
+func (h *Handler) subscribe(conn *websocket.Conn, writeMu *sync.Mutex, req rpcRequest) (string, error) {
+	eventType, filter, err := parseSubscribeParams(req.Params)
+	if err != nil {
+		return "", err
+	}
+
+	id, events, err := h.service.Subscribe(eventType, filter)
+	if err != nil {
+		return "", err
+	}
+
+	writeResult(conn, writeMu, req.ID, id)
+
+	go forward(conn, writeMu, id, events)
+
+	return id, nil
+}
+
+// forward streams events to the connection until the subscription's
+// channel is closed by an Unsubscribe call.
+func forward(conn *websocket.Conn, writeMu *sync.Mutex, id string, events <-chan pubsub.Event) {
+	for evt := range events {
+		result, err := json.Marshal(evt.Payload)
+		if err != nil {
+			continue
+		}
+
+		notification := rpcNotification{
+			JSONRPC: "2.0",
+			Method:  notificationMethod,
+			Params: rpcNotificationBody{
+				Subscription: id,
+				Result:       result,
+			},
+		}
+
+		writeMu.Lock()
+		err = conn.WriteJSON(notification)
+		writeMu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func parseSubscribeParams(params []json.RawMessage) (EventType, Filter, error) {
+	if len(params) == 0 {
+		return "", Filter{}, errMissingEventType
+	}
+
+	var eventType EventType
+	if err := json.Unmarshal(params[0], &eventType); err != nil {
+		return "", Filter{}, errMissingEventType
+	}
+
+	var filter Filter
+	if len(params) > 1 {
+		if err := json.Unmarshal(params[1], &filter); err != nil {
+			return "", Filter{}, err
+		}
+	}
+
+	return eventType, filter, nil
+}
+
+func unsubscribeID(req rpcRequest) (string, error) {
+	if len(req.Params) == 0 {
+		return "", errMissingSubscriptionID
+	}
+
+	var id string
+	if err := json.Unmarshal(req.Params[0], &id); err != nil {
+		return "", errMissingSubscriptionID
+	}
+	return id, nil
+}
+
+func writeResult(conn *websocket.Conn, writeMu *sync.Mutex, id json.RawMessage, result interface{}) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	_ = conn.WriteJSON(rpcResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func writeError(conn *websocket.Conn, writeMu *sync.Mutex, id json.RawMessage, err error) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	_ = conn.WriteJSON(rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: -32600, Message: err.Error()}})
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package subscribe
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/RavenProject/rosetta-ravencoin/pubsub"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Service fans block, mempool, and account activity events from the
+// indexer's processing loops out to WebSocket subscribers, via a
+// pubsub.Broker keyed by subscription id.
+type Service struct {
+	broker *pubsub.Broker
+}
+
+// NewService returns a Service backed by a freshly created Broker. Each
+// subscriber's buffer holds bufferSize events before the oldest one is
+// dropped to make room for the newest.
+func NewService(bufferSize int) *Service {
+	return &Service{broker: pubsub.NewBroker(bufferSize)}
+}
+
+// Subscribe registers a new subscription of the given type and returns
+// its id and event channel. The caller must eventually call Unsubscribe.
+func (s *Service) Subscribe(eventType EventType, filter Filter) (string, <-chan pubsub.Event, error) {
+	id, err := newSubscriptionID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	events := s.broker.Subscribe(id, string(eventType), filterFunc(eventType, filter))
+	return id, events, nil
+}
+
+// Unsubscribe tears down a subscription created with Subscribe.
+func (s *Service) Unsubscribe(id string) {
+	s.broker.Unsubscribe(id)
+}
+
+// PublishNewHead notifies NewHeads subscribers of a newly connected
+// block. It should be called from the indexer's block-processing loop.
+func (s *Service) PublishNewHead(block *types.BlockIdentifier) {
+	s.broker.Publish(string(NewHeads), block)
+}
+
+// PublishMempoolTransaction notifies NewMempoolTransactions subscribers
+// of a transaction observed in the mempool. It should be called from the
+// indexer's mempool poller.
+func (s *Service) PublishMempoolTransaction(transaction *types.TransactionIdentifier) {
+	s.broker.Publish(string(NewMempoolTransactions), transaction)
+}
+
+// PublishAccountActivity notifies AccountActivity subscribers that a
+// transaction touched an account in the given currency. event.Confirmations
+// should be set to the transaction's depth at the time of publication so
+// that Filter.MinConfirmations can be applied.
+func (s *Service) PublishAccountActivity(event *AccountActivityEvent) {
+	s.broker.Publish(string(AccountActivity), event)
+}
+
+// filterFunc adapts a Filter into a pubsub.Filter for the given event
+// type; only AccountActivity events carry fields a Filter can match
+// against.
+func filterFunc(eventType EventType, filter Filter) pubsub.Filter {
+	if eventType != AccountActivity {
+		return nil
+	}
+	if filter.Asset == "" && filter.AddressPrefix == "" && filter.MinConfirmations == 0 {
+		return nil
+	}
+
+	return func(evt pubsub.Event) bool {
+		activity, ok := evt.Payload.(*AccountActivityEvent)
+		if !ok {
+			return false
+		}
+		if filter.Asset != "" && activity.Currency != filter.Asset {
+			return false
+		}
+		if filter.AddressPrefix != "" && !strings.HasPrefix(activity.Address, filter.AddressPrefix) {
+			return false
+		}
+		if filter.MinConfirmations != 0 && activity.Confirmations < filter.MinConfirmations {
+			return false
+		}
+		return true
+	}
+}
+
+// newSubscriptionID returns a random hex subscription id, in the same
+// style as eth_subscribe's subscription ids.
+func newSubscriptionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package subscribe
+
+import "testing"
+
+func TestServiceAccountActivityFilter(t *testing.T) {
+	s := NewService(4)
+
+	id, events, err := s.Subscribe(AccountActivity, Filter{Asset: "RVN", AddressPrefix: "R9"})
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer s.Unsubscribe(id)
+
+	s.PublishAccountActivity(&AccountActivityEvent{Address: "R1xxx", Currency: "RVN"})
+	s.PublishAccountActivity(&AccountActivityEvent{Address: "R9match", Currency: "GOLD"})
+	s.PublishAccountActivity(&AccountActivityEvent{Address: "R9match", Currency: "RVN"})
+
+	select {
+	case evt := <-events:
+		activity := evt.Payload.(*AccountActivityEvent)
+		if activity.Address != "R9match" || activity.Currency != "RVN" {
+			t.Errorf("unexpected event delivered: %+v", activity)
+		}
+	default:
+		t.Fatal("expected the matching event to be queued")
+	}
+
+	select {
+	case evt := <-events:
+		t.Fatalf("did not expect a second event, got %+v", evt)
+	default:
+	}
+}
+
+func TestServiceAccountActivityMinConfirmations(t *testing.T) {
+	s := NewService(4)
+
+	id, events, err := s.Subscribe(AccountActivity, Filter{MinConfirmations: 6})
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer s.Unsubscribe(id)
+
+	s.PublishAccountActivity(&AccountActivityEvent{Address: "R1xxx", Currency: "RVN", Confirmations: 1})
+	s.PublishAccountActivity(&AccountActivityEvent{Address: "R1xxx", Currency: "RVN", Confirmations: 6})
+
+	select {
+	case evt := <-events:
+		activity := evt.Payload.(*AccountActivityEvent)
+		if activity.Confirmations != 6 {
+			t.Errorf("expected the 6-confirmation event, got %+v", activity)
+		}
+	default:
+		t.Fatal("expected the sufficiently-confirmed event to be queued")
+	}
+
+	select {
+	case evt := <-events:
+		t.Fatalf("did not expect a second event, got %+v", evt)
+	default:
+	}
+}
+
+func TestServiceNewHeads(t *testing.T) {
+	s := NewService(4)
+
+	id, events, err := s.Subscribe(NewHeads, Filter{})
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer s.Unsubscribe(id)
+
+	s.PublishMempoolTransaction(nil) // different topic, must not be delivered
+
+	select {
+	case evt := <-events:
+		t.Fatalf("did not expect a mempool event on the newHeads subscription, got %+v", evt)
+	default:
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The Ravencoin developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package subscribe adds a WebSocket subscription API on top of
+// services.Indexer, mirroring the filter/subscribe RPC pattern popularized
+// by modern Ethereum clients (eth_subscribe/eth_unsubscribe): a client
+// sends a JSON-RPC 2.0 "rosetta_subscribe" request naming an event type
+// and an optional filter, and receives a stream of
+// "rosetta_subscription" notifications in return.
+package subscribe
+
+import "encoding/json"
+
+// EventType identifies what a subscriber wants to be notified about.
+type EventType string
+
+// Supported subscription event types.
+const (
+	// NewHeads notifies on every new block accepted onto the best
+	// chain.
+	NewHeads EventType = "newHeads"
+
+	// NewMempoolTransactions notifies on every transaction the mempool
+	// poller observes.
+	NewMempoolTransactions EventType = "newMempoolTransactions"
+
+	// AccountActivity notifies when a transaction touches a specific
+	// account, optionally restricted to one asset.
+	AccountActivity EventType = "accountActivity"
+)
+
+// Filter narrows which events within a subscription's topic are
+// delivered to the caller. Every field is optional; a zero value places
+// no restriction on that dimension.
+type Filter struct {
+	// Asset restricts AccountActivity notifications to a single asset
+	// currency symbol (e.g. "RVN" or an asset name). Ignored for other
+	// event types.
+	Asset string `json:"asset,omitempty"`
+
+	// AddressPrefix restricts AccountActivity notifications to accounts
+	// whose address starts with this prefix.
+	AddressPrefix string `json:"address_prefix,omitempty"`
+
+	// MinConfirmations restricts AccountActivity notifications to those
+	// whose AccountActivityEvent.Confirmations is at least this deep.
+	// Events are published once, at whatever confirmation depth the
+	// caller observed them at, so this does not defer delivery until an
+	// event reaches the threshold later - it simply drops events that
+	// haven't reached it yet.
+	MinConfirmations int64 `json:"min_confirmations,omitempty"`
+}
+
+// rpcRequest is the client -> server JSON-RPC 2.0 envelope.
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// rpcResponse is the server -> client reply to an rpcRequest.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is an error reply per the JSON-RPC 2.0 spec.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcNotification is a server -> client push, sent for every event a
+// live subscription matches.
+type rpcNotification struct {
+	JSONRPC string              `json:"jsonrpc"`
+	Method  string              `json:"method"`
+	Params  rpcNotificationBody `json:"params"`
+}
+
+// notificationMethod is the fixed method name on every push, matching
+// the eth_subscription convention.
+const notificationMethod = "rosetta_subscription"
+
+// rpcNotificationBody is the params payload of an rpcNotification.
+type rpcNotificationBody struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// AccountActivityEvent is the notification payload delivered to
+// AccountActivity subscribers.
+type AccountActivityEvent struct {
+	Address               string `json:"address"`
+	Currency              string `json:"currency"`
+	TransactionIdentifier string `json:"transaction_identifier"`
+
+	// Confirmations is how many blocks deep the transaction was at the
+	// time the caller published this event. It is what
+	// Filter.MinConfirmations is compared against.
+	Confirmations int64 `json:"confirmations"`
+}